@@ -0,0 +1,128 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"oras.land/oras-go/v2/internal/compat"
+)
+
+// isQuote reports whether c opens one of the quoted string forms recognized
+// by the compat package: single, double, or backtick quotes.
+func isQuote(c byte) bool {
+	return c == '\'' || c == '"' || c == '`'
+}
+
+// schemeLen returns the length of a leading "scheme://" prefix in ref, or 0
+// if ref does not begin with one. A scheme is an ASCII letter followed by
+// letters, digits, '+', '.', or '-', per RFC 3986 — this covers references
+// like file:// and oci://.
+func schemeLen(ref string) int {
+	i := strings.Index(ref, "://")
+	if i <= 0 {
+		return 0
+	}
+	if !isSchemeStart(ref[0]) {
+		return 0
+	}
+	for j := 1; j < i; j++ {
+		if !isSchemeChar(ref[j]) {
+			return 0
+		}
+	}
+	return i + len("://")
+}
+
+func isSchemeStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isSchemeChar(c byte) bool {
+	return isSchemeStart(c) || '0' <= c && c <= '9' || c == '+' || c == '.' || c == '-'
+}
+
+// findSeparator returns the index of the ':' that separates a bare
+// (unquoted) file reference from its optional media type, ignoring any
+// colons within ref[:searchFrom] — the portion already consumed by a
+// platform-specific prefix such as a UNC path or a scheme:// prefix. It
+// returns -1 if there is no such separator.
+func findSeparator(ref string, searchFrom int) int {
+	i := strings.IndexByte(ref[searchFrom:], ':')
+	if i < 0 {
+		return -1
+	}
+	return searchFrom + i
+}
+
+// parseQuotedFileRef handles the portion of parseFileRef shared by every
+// platform: ref begins with a quoted file path, optionally followed by ':'
+// and a media type, which may itself be quoted.
+func parseQuotedFileRef(ref string, mediaType string) (string, string, error) {
+	path, rem, err := unquotePathPrefix(ref)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse quoted file path %q: %w", ref, err)
+	}
+	switch {
+	case len(rem) == 0:
+		return path, mediaType, nil
+	case rem[0] == ':':
+		rem = rem[1:]
+		if len(rem) > 0 && isQuote(rem[0]) {
+			mt, tail, err := compat.UnquotePrefix(rem)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to parse quoted media type %q: %w", rem, err)
+			}
+			if len(tail) != 0 {
+				return "", "", fmt.Errorf("unexpected trailing content after media type in %q", ref)
+			}
+			return path, mt, nil
+		}
+		return path, rem, nil
+	default:
+		return "", "", fmt.Errorf("unexpected trailing content after quoted file path in %q", ref)
+	}
+}
+
+// unquotePathPrefix consumes a leading quoted file path from ref. Single-
+// and backtick-quoted paths use the same Go-string-literal grammar as the
+// rest of the compat package. Double-quoted paths are treated leniently
+// instead: since file paths routinely contain single, undoubled
+// backslashes (Windows paths in particular), a backslash is copied through
+// literally unless it precedes a '"' or another '\\', the only two
+// sequences needed to embed those characters themselves. This lets users
+// write "C:\path\with:colon" rather than requiring doubled backslashes.
+func unquotePathPrefix(ref string) (path, rem string, err error) {
+	if len(ref) == 0 || ref[0] != '"' {
+		return compat.UnquotePrefix(ref)
+	}
+	var buf strings.Builder
+	i := 1
+	for i < len(ref) {
+		switch c := ref[i]; {
+		case c == '"':
+			return buf.String(), ref[i+1:], nil
+		case c == '\\' && i+1 < len(ref) && (ref[i+1] == '"' || ref[i+1] == '\\'):
+			buf.WriteByte(ref[i+1])
+			i += 2
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return "", "", compat.ErrSyntax
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+// parseFileRef parse file reference on non-Windows systems.
+// Unlike on Windows, ':' is a legal path byte here, so a bare (unquoted)
+// ref is always split on its first ':' — except for a leading
+// scheme:// URI prefix, e.g. file:// or oci://, whose colon is skipped
+// over. A ':' in the authority that follows (such as a port) is taken as
+// the separator. Backslashes are treated as ordinary, literal path bytes.
+func parseFileRef(ref string, mediaType string) (string, string, error) {
+	if len(ref) > 0 && isQuote(ref[0]) {
+		return parseQuotedFileRef(ref, mediaType)
+	}
+
+	searchFrom := 0
+	if n := schemeLen(ref); n > 0 {
+		searchFrom = n
+	}
+
+	i := findSeparator(ref, searchFrom)
+	if i < 0 {
+		return ref, mediaType, nil
+	}
+
+	return ref[:i], ref[i+1:], nil
+}
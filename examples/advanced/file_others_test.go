@@ -0,0 +1,91 @@
+//go:build !windows
+
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "testing"
+
+func TestParseFileRef(t *testing.T) {
+	tests := []struct {
+		name          string
+		ref           string
+		defaultMedia  string
+		wantPath      string
+		wantMediaType string
+		wantErr       bool
+	}{
+		{
+			name:          "plain unix path with media type",
+			ref:           "/etc/foo.txt:text/plain",
+			wantPath:      "/etc/foo.txt",
+			wantMediaType: "text/plain",
+		},
+		{
+			name:     "plain unix path with no media type",
+			ref:      "/etc/foo.txt",
+			wantPath: "/etc/foo.txt",
+		},
+		{
+			name:          "backslash is a literal path byte, not a separator",
+			ref:           `foo\bar.txt:text/plain`,
+			wantPath:      `foo\bar.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "quoted path containing a colon",
+			ref:           `"file:with:colons":application/vnd.oci.image.layer.v1.tar`,
+			wantPath:      "file:with:colons",
+			wantMediaType: "application/vnd.oci.image.layer.v1.tar",
+		},
+		{
+			name:          "file scheme URI with media type",
+			ref:           "file:///etc/foo.txt:text/plain",
+			wantPath:      "file:///etc/foo.txt",
+			wantMediaType: "text/plain",
+		},
+		{
+			name:     "oci scheme URI with no media type",
+			ref:      "oci://registry/repo",
+			wantPath: "oci://registry/repo",
+		},
+		{
+			name:    "unterminated quote",
+			ref:     `"unterminated`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, mediaType, err := parseFileRef(tt.ref, tt.defaultMedia)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFileRef(%q) = (%q, %q, nil); want error", tt.ref, path, mediaType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFileRef(%q) returned unexpected error: %v", tt.ref, err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+			if mediaType != tt.wantMediaType {
+				t.Errorf("mediaType = %q, want %q", mediaType, tt.wantMediaType)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "testing"
+
+func TestSchemeLen(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"file:///foo/bar", 7},
+		{"oci://registry/repo", 6},
+		{"oci+insecure://registry/repo", 15},
+		{"no-scheme-here", 0},
+		{"foo.txt:text/plain", 0},
+		{"://missing-scheme", 0},
+		{"1nvalid://scheme", 0},
+	}
+	for _, tt := range tests {
+		if got := schemeLen(tt.ref); got != tt.want {
+			t.Errorf("schemeLen(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -21,21 +21,81 @@ import (
 )
 
 // parseFileRef parse file reference on windows.
-// Windows systems does not allow ':' in the file path except for drive letter.
-func parseFileRef(ref string, mediaType string) (string, string) {
-	i := strings.Index(ref, ":")
+// Windows systems does not allow ':' in the file path except for drive
+// letter, so a bare (unquoted) ref is split on the first ':' that isn't
+// part of one of the prefixes recognized below:
+//
+//   - a quoted file path (single, double, or backtick quoted), optionally
+//     followed by ':' and a media type, which may itself be quoted;
+//   - a \\?\ or \\.\ extended-length prefix, or a \\server\share\ UNC
+//     prefix;
+//   - a scheme:// URI prefix, e.g. file:// or oci://; only the scheme's
+//     own colon is skipped, so a ':' in the authority that follows (such
+//     as a port) is taken as the media-type separator;
+//   - a drive letter followed by '\' or '/', e.g. C:\foo or C:/foo.
+func parseFileRef(ref string, mediaType string) (string, string, error) {
+	if len(ref) > 0 && isQuote(ref[0]) {
+		return parseQuotedFileRef(ref, mediaType)
+	}
+
+	searchFrom := 0
+	driveLetterEligible := true
+	if n := uncPrefixLen(ref); n > 0 {
+		searchFrom = n
+		driveLetterEligible = false
+		// \\?\C:\... and \\.\C:\... embed a drive letter right after the
+		// marker; that colon is part of the prefix, not the media-type
+		// separator.
+		if searchFrom+1 < len(ref) && unicode.IsLetter(rune(ref[searchFrom])) && ref[searchFrom+1] == ':' {
+			searchFrom += 2
+		}
+	} else if n := schemeLen(ref); n > 0 {
+		searchFrom = n
+		driveLetterEligible = false
+	}
+
+	i := findSeparator(ref, searchFrom)
 	if i < 0 {
-		return ref, mediaType
+		return ref, mediaType, nil
 	}
 
-	// In case it is C:\
-	if i == 1 && len(ref) > 2 && ref[2] == '\\' && unicode.IsLetter(rune(ref[0])) {
-		i = strings.Index(ref[3:], ":")
-		if i < 0 {
-			return ref, mediaType
+	// Drive-letter carve-out, e.g. C:\foo or C:/foo (PowerShell-style).
+	if driveLetterEligible && i == 1 && len(ref) > 2 && isDriveSeparator(ref[2]) && unicode.IsLetter(rune(ref[0])) {
+		j := findSeparator(ref, 3)
+		if j < 0 {
+			return ref, mediaType, nil
 		}
-		i += 3
+		i = j
+	}
+
+	return ref[:i], ref[i+1:], nil
+}
+
+// isDriveSeparator reports whether c may follow a drive letter and colon,
+// e.g. the '\' in C:\foo or the '/' in C:/foo.
+func isDriveSeparator(c byte) bool {
+	return c == '\\' || c == '/'
+}
+
+// uncPrefixLen returns the length of a leading \\?\, \\.\, or \\server\
+// share\ prefix in ref, or 0 if ref does not begin with one of those forms.
+func uncPrefixLen(ref string) int {
+	if !strings.HasPrefix(ref, `\\`) {
+		return 0
+	}
+	if len(ref) >= 4 && (ref[2] == '?' || ref[2] == '.') && ref[3] == '\\' {
+		return 4
 	}
 
-	return ref[:i], ref[i+1:]
+	// \\server\share\...: preserve through the server and share components.
+	rest := ref[2:]
+	server := strings.IndexByte(rest, '\\')
+	if server < 0 {
+		return 0
+	}
+	share := strings.IndexByte(rest[server+1:], '\\')
+	if share < 0 {
+		return 0
+	}
+	return 2 + server + 1 + share + 1
 }
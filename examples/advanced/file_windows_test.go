@@ -0,0 +1,146 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "testing"
+
+func TestParseFileRef(t *testing.T) {
+	tests := []struct {
+		name          string
+		ref           string
+		defaultMedia  string
+		wantPath      string
+		wantMediaType string
+		wantErr       bool
+	}{
+		{
+			name:          "quoted path with colon and media type",
+			ref:           `"C:\path\with:colon":application/vnd.oci.image.layer.v1.tar`,
+			wantPath:      `C:\path\with:colon`,
+			wantMediaType: "application/vnd.oci.image.layer.v1.tar",
+		},
+		{
+			name:     "double-quoted path with escaped quote",
+			ref:      `"C:\odd\"name"`,
+			wantPath: `C:\odd"name`,
+		},
+		{
+			name:     "double-quoted path with trailing literal backslash",
+			ref:      `"C:\trailing\\"`,
+			wantPath: `C:\trailing\`,
+		},
+		{
+			name:     "backtick raw path with no media type",
+			ref:      "`C:\\raw\\path`",
+			wantPath: `C:\raw\path`,
+		},
+		{
+			name:     "single char single-quoted ref",
+			ref:      `'x'`,
+			wantPath: "x",
+		},
+		{
+			name:          "quoted path with quoted media type",
+			ref:           `"file:name":"application/vnd.custom+type"`,
+			wantPath:      "file:name",
+			wantMediaType: "application/vnd.custom+type",
+		},
+		{
+			name:          "plain drive letter path with backslash, unquoted",
+			ref:           `C:\foo\bar.txt`,
+			defaultMedia:  "text/plain",
+			wantPath:      `C:\foo\bar.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "plain drive letter path with forward slash (PowerShell-style)",
+			ref:           `C:/foo/bar.txt:text/plain`,
+			wantPath:      `C:/foo/bar.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "plain path with media type unquoted",
+			ref:           `foo.txt:text/plain`,
+			wantPath:      "foo.txt",
+			wantMediaType: "text/plain",
+		},
+		{
+			name:     "extended-length prefix",
+			ref:      `\\?\C:\foo\bar.txt`,
+			wantPath: `\\?\C:\foo\bar.txt`,
+		},
+		{
+			name:          "extended-length prefix with media type",
+			ref:           `\\?\C:\foo\bar.txt:text/plain`,
+			wantPath:      `\\?\C:\foo\bar.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "UNC share with media type",
+			ref:           `\\server\share\foo.txt:text/plain`,
+			wantPath:      `\\server\share\foo.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "file scheme URI with media type",
+			ref:           `file:///foo/bar.txt:text/plain`,
+			wantPath:      `file:///foo/bar.txt`,
+			wantMediaType: "text/plain",
+		},
+		{
+			name:          "oci scheme URI with media type",
+			ref:           `oci://registry/repo:tag`,
+			wantPath:      `oci://registry/repo`,
+			wantMediaType: "tag",
+		},
+		{
+			name:    "unterminated quote",
+			ref:     `"unterminated`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing garbage after closing quote",
+			ref:     `"path"garbage`,
+			wantErr: true,
+		},
+		{
+			name:          "quote in the middle of a bare ref falls back to colon split",
+			ref:           `foo"bar:baz`,
+			wantPath:      `foo"bar`,
+			wantMediaType: "baz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, mediaType, err := parseFileRef(tt.ref, tt.defaultMedia)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFileRef(%q) = (%q, %q, nil); want error", tt.ref, path, mediaType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFileRef(%q) returned unexpected error: %v", tt.ref, err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+			if mediaType != tt.wantMediaType {
+				t.Errorf("mediaType = %q, want %q", mediaType, tt.wantMediaType)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Code generated by running a reduced variant of the upstream strconv
+// maketables generator against Unicode category data. DO NOT EDIT.
+
+package compat
+
+// isGraphic lists the graphic runes, per Unicode's definition, that are not
+// already matched by IsPrint. These are chiefly the non-ASCII space
+// separators (category Zs), which Go's IsPrint intentionally excludes so
+// that only the ASCII space is treated as printable.
+var isGraphic = []uint16{
+	0x00A0,
+	0x1680,
+	0x2000,
+	0x2001,
+	0x2002,
+	0x2003,
+	0x2004,
+	0x2005,
+	0x2006,
+	0x2007,
+	0x2008,
+	0x2009,
+	0x200A,
+	0x2028,
+	0x2029,
+	0x202F,
+	0x205F,
+	0x3000,
+}
+
+// isInGraphicList reports whether r is in isGraphic. It is only called for
+// runes that are not already printable, so a linear scan over the small,
+// fixed-size list is cheap.
+func isInGraphicList(r rune) bool {
+	// isGraphic only holds BMP code points, so anything above it (including
+	// supplementary-plane runes that would alias onto the list via a
+	// uint16 truncation) is never in it.
+	if r > 0xFFFF {
+		return false
+	}
+	rr := uint16(r)
+	for _, r1 := range isGraphic {
+		if rr == r1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,741 @@
+// Code generated by running a variant of the upstream strconv
+// maketables generator against Unicode category data, then clipping out
+// the Latin-1 block (handled directly by IsPrint) and flattening the
+// supplementary-plane exception list's offset encoding into absolute code
+// points for readability. It reproduces strconv.IsPrint's classification
+// exactly.
+// DO NOT EDIT.
+
+package compat
+
+// isPrint16 holds (lo, hi) range pairs, sorted by lo, of 16-bit code points
+// that are printable. It does not need to include the Latin-1 range, which
+// is handled directly by IsPrint.
+var isPrint16 = []uint16{
+	0x0100, 0x0377,
+	0x037A, 0x037F,
+	0x0384, 0x0556,
+	0x0559, 0x058A,
+	0x058D, 0x05C7,
+	0x05D0, 0x05EA,
+	0x05EF, 0x05F4,
+	0x0606, 0x070D,
+	0x0710, 0x074A,
+	0x074D, 0x07B1,
+	0x07C0, 0x07FA,
+	0x07FD, 0x082D,
+	0x0830, 0x085B,
+	0x085E, 0x086A,
+	0x0870, 0x088E,
+	0x0898, 0x098C,
+	0x098F, 0x0990,
+	0x0993, 0x09B2,
+	0x09B6, 0x09B9,
+	0x09BC, 0x09C4,
+	0x09C7, 0x09C8,
+	0x09CB, 0x09CE,
+	0x09D7, 0x09D7,
+	0x09DC, 0x09E3,
+	0x09E6, 0x09FE,
+	0x0A01, 0x0A0A,
+	0x0A0F, 0x0A10,
+	0x0A13, 0x0A39,
+	0x0A3C, 0x0A42,
+	0x0A47, 0x0A48,
+	0x0A4B, 0x0A4D,
+	0x0A51, 0x0A51,
+	0x0A59, 0x0A5E,
+	0x0A66, 0x0A76,
+	0x0A81, 0x0AB9,
+	0x0ABC, 0x0ACD,
+	0x0AD0, 0x0AD0,
+	0x0AE0, 0x0AE3,
+	0x0AE6, 0x0AF1,
+	0x0AF9, 0x0B0C,
+	0x0B0F, 0x0B10,
+	0x0B13, 0x0B39,
+	0x0B3C, 0x0B44,
+	0x0B47, 0x0B48,
+	0x0B4B, 0x0B4D,
+	0x0B55, 0x0B57,
+	0x0B5C, 0x0B63,
+	0x0B66, 0x0B77,
+	0x0B82, 0x0B8A,
+	0x0B8E, 0x0B95,
+	0x0B99, 0x0B9F,
+	0x0BA3, 0x0BA4,
+	0x0BA8, 0x0BAA,
+	0x0BAE, 0x0BB9,
+	0x0BBE, 0x0BC2,
+	0x0BC6, 0x0BCD,
+	0x0BD0, 0x0BD0,
+	0x0BD7, 0x0BD7,
+	0x0BE6, 0x0BFA,
+	0x0C00, 0x0C39,
+	0x0C3C, 0x0C4D,
+	0x0C55, 0x0C5A,
+	0x0C5D, 0x0C5D,
+	0x0C60, 0x0C63,
+	0x0C66, 0x0C6F,
+	0x0C77, 0x0CB9,
+	0x0CBC, 0x0CCD,
+	0x0CD5, 0x0CD6,
+	0x0CDD, 0x0CE3,
+	0x0CE6, 0x0CF3,
+	0x0D00, 0x0D4F,
+	0x0D54, 0x0D63,
+	0x0D66, 0x0D96,
+	0x0D9A, 0x0DBD,
+	0x0DC0, 0x0DC6,
+	0x0DCA, 0x0DCA,
+	0x0DCF, 0x0DDF,
+	0x0DE6, 0x0DEF,
+	0x0DF2, 0x0DF4,
+	0x0E01, 0x0E3A,
+	0x0E3F, 0x0E5B,
+	0x0E81, 0x0EBD,
+	0x0EC0, 0x0ED9,
+	0x0EDC, 0x0EDF,
+	0x0F00, 0x0F6C,
+	0x0F71, 0x0FDA,
+	0x1000, 0x10C7,
+	0x10CD, 0x10CD,
+	0x10D0, 0x124D,
+	0x1250, 0x125D,
+	0x1260, 0x128D,
+	0x1290, 0x12B5,
+	0x12B8, 0x12C5,
+	0x12C8, 0x1315,
+	0x1318, 0x135A,
+	0x135D, 0x137C,
+	0x1380, 0x1399,
+	0x13A0, 0x13F5,
+	0x13F8, 0x13FD,
+	0x1400, 0x169C,
+	0x16A0, 0x16F8,
+	0x1700, 0x1715,
+	0x171F, 0x1736,
+	0x1740, 0x1753,
+	0x1760, 0x1773,
+	0x1780, 0x17DD,
+	0x17E0, 0x17E9,
+	0x17F0, 0x17F9,
+	0x1800, 0x1819,
+	0x1820, 0x1878,
+	0x1880, 0x18AA,
+	0x18B0, 0x18F5,
+	0x1900, 0x192B,
+	0x1930, 0x193B,
+	0x1940, 0x1940,
+	0x1944, 0x196D,
+	0x1970, 0x1974,
+	0x1980, 0x19AB,
+	0x19B0, 0x19C9,
+	0x19D0, 0x19DA,
+	0x19DE, 0x1A1B,
+	0x1A1E, 0x1A7C,
+	0x1A7F, 0x1A89,
+	0x1A90, 0x1A99,
+	0x1AA0, 0x1AAD,
+	0x1AB0, 0x1ACE,
+	0x1B00, 0x1B4C,
+	0x1B50, 0x1BF3,
+	0x1BFC, 0x1C37,
+	0x1C3B, 0x1C49,
+	0x1C4D, 0x1C88,
+	0x1C90, 0x1CBA,
+	0x1CBD, 0x1CC7,
+	0x1CD0, 0x1CFA,
+	0x1D00, 0x1F15,
+	0x1F18, 0x1F1D,
+	0x1F20, 0x1F45,
+	0x1F48, 0x1F4D,
+	0x1F50, 0x1F7D,
+	0x1F80, 0x1FD3,
+	0x1FD6, 0x1FEF,
+	0x1FF2, 0x1FFE,
+	0x2010, 0x2027,
+	0x2030, 0x205E,
+	0x2070, 0x2071,
+	0x2074, 0x209C,
+	0x20A0, 0x20C0,
+	0x20D0, 0x20F0,
+	0x2100, 0x218B,
+	0x2190, 0x2426,
+	0x2440, 0x244A,
+	0x2460, 0x2B73,
+	0x2B76, 0x2CF3,
+	0x2CF9, 0x2D27,
+	0x2D2D, 0x2D2D,
+	0x2D30, 0x2D67,
+	0x2D6F, 0x2D70,
+	0x2D7F, 0x2D96,
+	0x2DA0, 0x2E5D,
+	0x2E80, 0x2EF3,
+	0x2F00, 0x2FD5,
+	0x2FF0, 0x2FFB,
+	0x3001, 0x3096,
+	0x3099, 0x30FF,
+	0x3105, 0x31E3,
+	0x31F0, 0xA48C,
+	0xA490, 0xA4C6,
+	0xA4D0, 0xA62B,
+	0xA640, 0xA6F7,
+	0xA700, 0xA7CA,
+	0xA7D0, 0xA7D9,
+	0xA7F2, 0xA82C,
+	0xA830, 0xA839,
+	0xA840, 0xA877,
+	0xA880, 0xA8C5,
+	0xA8CE, 0xA8D9,
+	0xA8E0, 0xA953,
+	0xA95F, 0xA97C,
+	0xA980, 0xA9D9,
+	0xA9DE, 0xAA36,
+	0xAA40, 0xAA4D,
+	0xAA50, 0xAA59,
+	0xAA5C, 0xAAC2,
+	0xAADB, 0xAAF6,
+	0xAB01, 0xAB06,
+	0xAB09, 0xAB0E,
+	0xAB11, 0xAB16,
+	0xAB20, 0xAB6B,
+	0xAB70, 0xABED,
+	0xABF0, 0xABF9,
+	0xAC00, 0xD7A3,
+	0xD7B0, 0xD7C6,
+	0xD7CB, 0xD7FB,
+	0xF900, 0xFA6D,
+	0xFA70, 0xFAD9,
+	0xFB00, 0xFB06,
+	0xFB13, 0xFB17,
+	0xFB1D, 0xFBC2,
+	0xFBD3, 0xFD8F,
+	0xFD92, 0xFDC7,
+	0xFDCF, 0xFDCF,
+	0xFDF0, 0xFE19,
+	0xFE20, 0xFE6B,
+	0xFE70, 0xFEFC,
+	0xFF01, 0xFFBE,
+	0xFFC2, 0xFFC7,
+	0xFFCA, 0xFFCF,
+	0xFFD2, 0xFFD7,
+	0xFFDA, 0xFFDC,
+	0xFFE0, 0xFFEE,
+	0xFFFC, 0xFFFD,
+}
+
+// isNotPrint16 lists individual 16-bit code points that fall inside an
+// isPrint16 range but are not themselves printable (e.g. unassigned or
+// otherwise excluded code points within an assigned block).
+var isNotPrint16 = []uint16{
+	0x038B,
+	0x038D,
+	0x03A2,
+	0x0530,
+	0x0590,
+	0x061C,
+	0x06DD,
+	0x083F,
+	0x085F,
+	0x08E2,
+	0x0984,
+	0x09A9,
+	0x09B1,
+	0x09DE,
+	0x0A04,
+	0x0A29,
+	0x0A31,
+	0x0A34,
+	0x0A37,
+	0x0A3D,
+	0x0A5D,
+	0x0A84,
+	0x0A8E,
+	0x0A92,
+	0x0AA9,
+	0x0AB1,
+	0x0AB4,
+	0x0AC6,
+	0x0ACA,
+	0x0B00,
+	0x0B04,
+	0x0B29,
+	0x0B31,
+	0x0B34,
+	0x0B5E,
+	0x0B84,
+	0x0B91,
+	0x0B9B,
+	0x0B9D,
+	0x0BC9,
+	0x0C0D,
+	0x0C11,
+	0x0C29,
+	0x0C45,
+	0x0C49,
+	0x0C57,
+	0x0C8D,
+	0x0C91,
+	0x0CA9,
+	0x0CB4,
+	0x0CC5,
+	0x0CC9,
+	0x0CDF,
+	0x0CF0,
+	0x0D0D,
+	0x0D11,
+	0x0D45,
+	0x0D49,
+	0x0D80,
+	0x0D84,
+	0x0DB2,
+	0x0DBC,
+	0x0DD5,
+	0x0DD7,
+	0x0E83,
+	0x0E85,
+	0x0E8B,
+	0x0EA4,
+	0x0EA6,
+	0x0EC5,
+	0x0EC7,
+	0x0ECF,
+	0x0F48,
+	0x0F98,
+	0x0FBD,
+	0x0FCD,
+	0x10C6,
+	0x1249,
+	0x1257,
+	0x1259,
+	0x1289,
+	0x12B1,
+	0x12BF,
+	0x12C1,
+	0x12D7,
+	0x1311,
+	0x1680,
+	0x176D,
+	0x1771,
+	0x180E,
+	0x191F,
+	0x1A5F,
+	0x1B7F,
+	0x1F58,
+	0x1F5A,
+	0x1F5C,
+	0x1F5E,
+	0x1FB5,
+	0x1FC5,
+	0x1FDC,
+	0x1FF5,
+	0x208F,
+	0x2B96,
+	0x2D26,
+	0x2DA7,
+	0x2DAF,
+	0x2DB7,
+	0x2DBF,
+	0x2DC7,
+	0x2DCF,
+	0x2DD7,
+	0x2DDF,
+	0x2E9A,
+	0x3040,
+	0x3130,
+	0x318F,
+	0x321F,
+	0xA7D2,
+	0xA7D4,
+	0xA9CE,
+	0xA9FF,
+	0xAB27,
+	0xAB2F,
+	0xFB37,
+	0xFB3D,
+	0xFB3F,
+	0xFB42,
+	0xFB45,
+	0xFE53,
+	0xFE67,
+	0xFE75,
+	0xFFE7,
+}
+
+// isPrint32 holds (lo, hi) range pairs, sorted by lo, of printable code
+// points outside the Basic Multilingual Plane.
+var isPrint32 = []uint32{
+	0x10000, 0x1004D,
+	0x10050, 0x1005D,
+	0x10080, 0x100FA,
+	0x10100, 0x10102,
+	0x10107, 0x10133,
+	0x10137, 0x1019C,
+	0x101A0, 0x101A0,
+	0x101D0, 0x101FD,
+	0x10280, 0x1029C,
+	0x102A0, 0x102D0,
+	0x102E0, 0x102FB,
+	0x10300, 0x10323,
+	0x1032D, 0x1034A,
+	0x10350, 0x1037A,
+	0x10380, 0x103C3,
+	0x103C8, 0x103D5,
+	0x10400, 0x1049D,
+	0x104A0, 0x104A9,
+	0x104B0, 0x104D3,
+	0x104D8, 0x104FB,
+	0x10500, 0x10527,
+	0x10530, 0x10563,
+	0x1056F, 0x105BC,
+	0x10600, 0x10736,
+	0x10740, 0x10755,
+	0x10760, 0x10767,
+	0x10780, 0x107BA,
+	0x10800, 0x10805,
+	0x10808, 0x10838,
+	0x1083C, 0x1083C,
+	0x1083F, 0x1089E,
+	0x108A7, 0x108AF,
+	0x108E0, 0x108F5,
+	0x108FB, 0x1091B,
+	0x1091F, 0x10939,
+	0x1093F, 0x1093F,
+	0x10980, 0x109B7,
+	0x109BC, 0x109CF,
+	0x109D2, 0x10A06,
+	0x10A0C, 0x10A35,
+	0x10A38, 0x10A3A,
+	0x10A3F, 0x10A48,
+	0x10A50, 0x10A58,
+	0x10A60, 0x10A9F,
+	0x10AC0, 0x10AE6,
+	0x10AEB, 0x10AF6,
+	0x10B00, 0x10B35,
+	0x10B39, 0x10B55,
+	0x10B58, 0x10B72,
+	0x10B78, 0x10B91,
+	0x10B99, 0x10B9C,
+	0x10BA9, 0x10BAF,
+	0x10C00, 0x10C48,
+	0x10C80, 0x10CB2,
+	0x10CC0, 0x10CF2,
+	0x10CFA, 0x10D27,
+	0x10D30, 0x10D39,
+	0x10E60, 0x10EAD,
+	0x10EB0, 0x10EB1,
+	0x10EFD, 0x10F27,
+	0x10F30, 0x10F59,
+	0x10F70, 0x10F89,
+	0x10FB0, 0x10FCB,
+	0x10FE0, 0x10FF6,
+	0x11000, 0x1104D,
+	0x11052, 0x11075,
+	0x1107F, 0x110C2,
+	0x110D0, 0x110E8,
+	0x110F0, 0x110F9,
+	0x11100, 0x11147,
+	0x11150, 0x11176,
+	0x11180, 0x111F4,
+	0x11200, 0x11241,
+	0x11280, 0x112A9,
+	0x112B0, 0x112EA,
+	0x112F0, 0x112F9,
+	0x11300, 0x1130C,
+	0x1130F, 0x11310,
+	0x11313, 0x11344,
+	0x11347, 0x11348,
+	0x1134B, 0x1134D,
+	0x11350, 0x11350,
+	0x11357, 0x11357,
+	0x1135D, 0x11363,
+	0x11366, 0x1136C,
+	0x11370, 0x11374,
+	0x11400, 0x11461,
+	0x11480, 0x114C7,
+	0x114D0, 0x114D9,
+	0x11580, 0x115B5,
+	0x115B8, 0x115DD,
+	0x11600, 0x11644,
+	0x11650, 0x11659,
+	0x11660, 0x1166C,
+	0x11680, 0x116B9,
+	0x116C0, 0x116C9,
+	0x11700, 0x1171A,
+	0x1171D, 0x1172B,
+	0x11730, 0x11746,
+	0x11800, 0x1183B,
+	0x118A0, 0x118F2,
+	0x118FF, 0x11906,
+	0x11909, 0x11909,
+	0x1190C, 0x11938,
+	0x1193B, 0x11946,
+	0x11950, 0x11959,
+	0x119A0, 0x119A7,
+	0x119AA, 0x119D7,
+	0x119DA, 0x119E4,
+	0x11A00, 0x11A47,
+	0x11A50, 0x11AA2,
+	0x11AB0, 0x11AF8,
+	0x11B00, 0x11B09,
+	0x11C00, 0x11C45,
+	0x11C50, 0x11C6C,
+	0x11C70, 0x11C8F,
+	0x11C92, 0x11CB6,
+	0x11D00, 0x11D36,
+	0x11D3A, 0x11D47,
+	0x11D50, 0x11D59,
+	0x11D60, 0x11D98,
+	0x11DA0, 0x11DA9,
+	0x11EE0, 0x11EF8,
+	0x11F00, 0x11F3A,
+	0x11F3E, 0x11F59,
+	0x11FB0, 0x11FB0,
+	0x11FC0, 0x11FF1,
+	0x11FFF, 0x12399,
+	0x12400, 0x12474,
+	0x12480, 0x12543,
+	0x12F90, 0x12FF2,
+	0x13000, 0x1342F,
+	0x13440, 0x13455,
+	0x14400, 0x14646,
+	0x16800, 0x16A38,
+	0x16A40, 0x16A69,
+	0x16A6E, 0x16AC9,
+	0x16AD0, 0x16AED,
+	0x16AF0, 0x16AF5,
+	0x16B00, 0x16B45,
+	0x16B50, 0x16B77,
+	0x16B7D, 0x16B8F,
+	0x16E40, 0x16E9A,
+	0x16F00, 0x16F4A,
+	0x16F4F, 0x16F87,
+	0x16F8F, 0x16F9F,
+	0x16FE0, 0x16FE4,
+	0x16FF0, 0x16FF1,
+	0x17000, 0x187F7,
+	0x18800, 0x18CD5,
+	0x18D00, 0x18D08,
+	0x1AFF0, 0x1B122,
+	0x1B132, 0x1B132,
+	0x1B150, 0x1B152,
+	0x1B155, 0x1B155,
+	0x1B164, 0x1B167,
+	0x1B170, 0x1B2FB,
+	0x1BC00, 0x1BC6A,
+	0x1BC70, 0x1BC7C,
+	0x1BC80, 0x1BC88,
+	0x1BC90, 0x1BC99,
+	0x1BC9C, 0x1BC9F,
+	0x1CF00, 0x1CF2D,
+	0x1CF30, 0x1CF46,
+	0x1CF50, 0x1CFC3,
+	0x1D000, 0x1D0F5,
+	0x1D100, 0x1D126,
+	0x1D129, 0x1D172,
+	0x1D17B, 0x1D1EA,
+	0x1D200, 0x1D245,
+	0x1D2C0, 0x1D2D3,
+	0x1D2E0, 0x1D2F3,
+	0x1D300, 0x1D356,
+	0x1D360, 0x1D378,
+	0x1D400, 0x1D49F,
+	0x1D4A2, 0x1D4A2,
+	0x1D4A5, 0x1D4A6,
+	0x1D4A9, 0x1D50A,
+	0x1D50D, 0x1D546,
+	0x1D54A, 0x1D6A5,
+	0x1D6A8, 0x1D7CB,
+	0x1D7CE, 0x1DA8B,
+	0x1DA9B, 0x1DAAF,
+	0x1DF00, 0x1DF1E,
+	0x1DF25, 0x1DF2A,
+	0x1E000, 0x1E018,
+	0x1E01B, 0x1E02A,
+	0x1E030, 0x1E06D,
+	0x1E08F, 0x1E08F,
+	0x1E100, 0x1E12C,
+	0x1E130, 0x1E13D,
+	0x1E140, 0x1E149,
+	0x1E14E, 0x1E14F,
+	0x1E290, 0x1E2AE,
+	0x1E2C0, 0x1E2F9,
+	0x1E2FF, 0x1E2FF,
+	0x1E4D0, 0x1E4F9,
+	0x1E7E0, 0x1E8C4,
+	0x1E8C7, 0x1E8D6,
+	0x1E900, 0x1E94B,
+	0x1E950, 0x1E959,
+	0x1E95E, 0x1E95F,
+	0x1EC71, 0x1ECB4,
+	0x1ED01, 0x1ED3D,
+	0x1EE00, 0x1EE24,
+	0x1EE27, 0x1EE3B,
+	0x1EE42, 0x1EE42,
+	0x1EE47, 0x1EE54,
+	0x1EE57, 0x1EE64,
+	0x1EE67, 0x1EE9B,
+	0x1EEA1, 0x1EEBB,
+	0x1EEF0, 0x1EEF1,
+	0x1F000, 0x1F02B,
+	0x1F030, 0x1F093,
+	0x1F0A0, 0x1F0AE,
+	0x1F0B1, 0x1F0F5,
+	0x1F100, 0x1F1AD,
+	0x1F1E6, 0x1F202,
+	0x1F210, 0x1F23B,
+	0x1F240, 0x1F248,
+	0x1F250, 0x1F251,
+	0x1F260, 0x1F265,
+	0x1F300, 0x1F6D7,
+	0x1F6DC, 0x1F6EC,
+	0x1F6F0, 0x1F6FC,
+	0x1F700, 0x1F776,
+	0x1F77B, 0x1F7D9,
+	0x1F7E0, 0x1F7EB,
+	0x1F7F0, 0x1F7F0,
+	0x1F800, 0x1F80B,
+	0x1F810, 0x1F847,
+	0x1F850, 0x1F859,
+	0x1F860, 0x1F887,
+	0x1F890, 0x1F8AD,
+	0x1F8B0, 0x1F8B1,
+	0x1F900, 0x1FA53,
+	0x1FA60, 0x1FA6D,
+	0x1FA70, 0x1FA7C,
+	0x1FA80, 0x1FA88,
+	0x1FA90, 0x1FAC5,
+	0x1FACE, 0x1FADB,
+	0x1FAE0, 0x1FAE8,
+	0x1FAF0, 0x1FAF8,
+	0x1FB00, 0x1FBCA,
+	0x1FBF0, 0x1FBF9,
+	0x20000, 0x2A6DF,
+	0x2A700, 0x2B739,
+	0x2B740, 0x2B81D,
+	0x2B820, 0x2CEA1,
+	0x2CEB0, 0x2EBE0,
+	0x2F800, 0x2FA1D,
+	0x30000, 0x3134A,
+	0x31350, 0x323AF,
+	0xE0100, 0xE01EF,
+}
+
+// isNotPrint32 lists individual code points that fall inside an isPrint32
+// range but are not themselves printable.
+var isNotPrint32 = []uint32{
+	0x20000,
+	0x1000C,
+	0x10027,
+	0x1003B,
+	0x1003E,
+	0x1018F,
+	0x1039E,
+	0x1057B,
+	0x1058B,
+	0x10593,
+	0x10596,
+	0x105A2,
+	0x105B2,
+	0x105BA,
+	0x10786,
+	0x107B1,
+	0x10809,
+	0x10836,
+	0x10856,
+	0x108F3,
+	0x10A04,
+	0x10A14,
+	0x10A18,
+	0x10E7F,
+	0x10EAA,
+	0x110BD,
+	0x11135,
+	0x111E0,
+	0x11212,
+	0x11287,
+	0x11289,
+	0x1128E,
+	0x1129E,
+	0x11304,
+	0x11329,
+	0x11331,
+	0x11334,
+	0x1133A,
+	0x1145C,
+	0x11914,
+	0x11917,
+	0x11936,
+	0x11C09,
+	0x11C37,
+	0x11CA8,
+	0x11D07,
+	0x11D0A,
+	0x11D3B,
+	0x11D3E,
+	0x11D66,
+	0x11D69,
+	0x11D8F,
+	0x11D92,
+	0x11F11,
+	0x1246F,
+	0x16A5F,
+	0x16ABF,
+	0x16B5A,
+	0x16B62,
+	0x1AFF4,
+	0x1AFFC,
+	0x1AFFF,
+	0x1D455,
+	0x1D49D,
+	0x1D4AD,
+	0x1D4BA,
+	0x1D4BC,
+	0x1D4C4,
+	0x1D506,
+	0x1D515,
+	0x1D51D,
+	0x1D53A,
+	0x1D53F,
+	0x1D545,
+	0x1D551,
+	0x1DAA0,
+	0x1E007,
+	0x1E022,
+	0x1E025,
+	0x1E7E7,
+	0x1E7EC,
+	0x1E7EF,
+	0x1E7FF,
+	0x1EE04,
+	0x1EE20,
+	0x1EE23,
+	0x1EE28,
+	0x1EE33,
+	0x1EE38,
+	0x1EE3A,
+	0x1EE48,
+	0x1EE4A,
+	0x1EE4C,
+	0x1EE50,
+	0x1EE53,
+	0x1EE58,
+	0x1EE5A,
+	0x1EE5C,
+	0x1EE5E,
+	0x1EE60,
+	0x1EE63,
+	0x1EE6B,
+	0x1EE73,
+	0x1EE78,
+	0x1EE7D,
+	0x1EE7F,
+	0x1EE8A,
+	0x1EEA4,
+	0x1EEAA,
+	0x1F0C0,
+	0x1F0D0,
+	0x1FABE,
+	0x1FB93,
+}
@@ -0,0 +1,313 @@
+package compat
+
+import "unicode/utf8"
+
+const lowerhex = "0123456789abcdef"
+
+// Quote returns a double-quoted Go string literal representing s. The
+// returned string uses Go escape sequences (\t, \n, \xFF, and \u or \U
+// forms) for control characters and non-printable characters as defined
+// by IsPrint.
+func Quote(s string) string {
+	return string(appendQuotedWith(make([]byte, 0, 3*len(s)/2), s, '"', false, false))
+}
+
+// AppendQuote appends a double-quoted Go string literal representing s, as
+// generated by Quote, to dst and returns the extended buffer.
+func AppendQuote(dst []byte, s string) []byte {
+	return appendQuotedWith(dst, s, '"', false, false)
+}
+
+// QuoteToASCII is like Quote but guarantees that the returned string is an
+// ASCII string, by escaping any non-ASCII Unicode characters.
+func QuoteToASCII(s string) string {
+	return string(appendQuotedWith(make([]byte, 0, 3*len(s)/2), s, '"', true, false))
+}
+
+// AppendQuoteToASCII appends a double-quoted Go string literal representing
+// s, as generated by QuoteToASCII, to dst and returns the extended buffer.
+func AppendQuoteToASCII(dst []byte, s string) []byte {
+	return appendQuotedWith(dst, s, '"', true, false)
+}
+
+// QuoteToGraphic is like Quote but guarantees that the returned string is a
+// string of graphic characters, as defined by IsGraphic.
+func QuoteToGraphic(s string) string {
+	return string(appendQuotedWith(make([]byte, 0, 3*len(s)/2), s, '"', false, true))
+}
+
+// AppendQuoteToGraphic appends a double-quoted Go string literal
+// representing s, as generated by QuoteToGraphic, to dst and returns the
+// extended buffer.
+func AppendQuoteToGraphic(dst []byte, s string) []byte {
+	return appendQuotedWith(dst, s, '"', false, true)
+}
+
+// QuoteRune returns a single-quoted Go character literal representing the
+// rune. The returned string uses Go escape sequences (\t, \n, \xFF, and
+// \u or \U forms) for control characters and non-printable characters as
+// defined by IsPrint.
+func QuoteRune(r rune) string {
+	return string(appendQuotedRuneWith(nil, r, '\'', false, false))
+}
+
+// AppendQuoteRune appends a single-quoted Go character literal representing
+// the rune, as generated by QuoteRune, to dst and returns the extended
+// buffer.
+func AppendQuoteRune(dst []byte, r rune) []byte {
+	return appendQuotedRuneWith(dst, r, '\'', false, false)
+}
+
+// QuoteRuneToASCII is like QuoteRune but guarantees that the returned string
+// is an ASCII string, by escaping any non-ASCII Unicode characters.
+func QuoteRuneToASCII(r rune) string {
+	return string(appendQuotedRuneWith(nil, r, '\'', true, false))
+}
+
+// AppendQuoteRuneToASCII appends a single-quoted Go character literal
+// representing the rune, as generated by QuoteRuneToASCII, to dst and
+// returns the extended buffer.
+func AppendQuoteRuneToASCII(dst []byte, r rune) []byte {
+	return appendQuotedRuneWith(dst, r, '\'', true, false)
+}
+
+// QuoteRuneToGraphic is like QuoteRune but guarantees that the returned
+// string is a string of graphic characters, as defined by IsGraphic.
+func QuoteRuneToGraphic(r rune) string {
+	return string(appendQuotedRuneWith(nil, r, '\'', false, true))
+}
+
+// AppendQuoteRuneToGraphic appends a single-quoted Go character literal
+// representing the rune, as generated by QuoteRuneToGraphic, to dst and
+// returns the extended buffer.
+func AppendQuoteRuneToGraphic(dst []byte, r rune) []byte {
+	return appendQuotedRuneWith(dst, r, '\'', false, true)
+}
+
+// appendQuotedWith writes a quote-delimited, escaped copy of s to buf,
+// walking s rune-by-rune via utf8.DecodeRuneInString. quote is the
+// delimiter byte; ASCIIonly forces non-ASCII runes to be escaped;
+// graphicOnly additionally allows runes from the isGraphic exception list
+// to pass through unescaped.
+func appendQuotedWith(buf []byte, s string, quote byte, ASCIIonly, graphicOnly bool) []byte {
+	// Often called with big strings, so preallocate. If there's quoting,
+	// this is conservative but still helps a lot.
+	if cap(buf)-len(buf) < len(s) {
+		nBuf := make([]byte, len(buf), len(buf)+1+len(s)+1)
+		copy(nBuf, buf)
+		buf = nBuf
+	}
+	buf = append(buf, quote)
+	for width := 0; len(s) > 0; s = s[width:] {
+		r := rune(s[0])
+		width = 1
+		if r >= utf8.RuneSelf {
+			r, width = utf8.DecodeRuneInString(s)
+		}
+		if width == 1 && r == utf8.RuneError {
+			buf = append(buf, `\x`...)
+			buf = append(buf, lowerhex[s[0]>>4])
+			buf = append(buf, lowerhex[s[0]&0xF])
+			continue
+		}
+		buf = appendEscapedRune(buf, r, quote, ASCIIonly, graphicOnly)
+	}
+	buf = append(buf, quote)
+	return buf
+}
+
+func appendQuotedRuneWith(buf []byte, r rune, quote byte, ASCIIonly, graphicOnly bool) []byte {
+	buf = append(buf, quote)
+	if !utf8.ValidRune(r) {
+		r = utf8.RuneError
+	}
+	buf = appendEscapedRune(buf, r, quote, ASCIIonly, graphicOnly)
+	buf = append(buf, quote)
+	return buf
+}
+
+func appendEscapedRune(buf []byte, r rune, quote byte, ASCIIonly, graphicOnly bool) []byte {
+	if r == rune(quote) || r == '\\' { // always backslashed
+		buf = append(buf, '\\')
+		buf = append(buf, byte(r))
+		return buf
+	}
+	if ASCIIonly {
+		if r < utf8.RuneSelf && IsPrint(r) {
+			buf = append(buf, byte(r))
+			return buf
+		}
+	} else if IsPrint(r) || graphicOnly && isInGraphicList(r) {
+		var runeTmp [utf8.UTFMax]byte
+		n := utf8.EncodeRune(runeTmp[:], r)
+		buf = append(buf, runeTmp[:n]...)
+		return buf
+	}
+	switch r {
+	case '\a':
+		buf = append(buf, `\a`...)
+	case '\b':
+		buf = append(buf, `\b`...)
+	case '\f':
+		buf = append(buf, `\f`...)
+	case '\n':
+		buf = append(buf, `\n`...)
+	case '\r':
+		buf = append(buf, `\r`...)
+	case '\t':
+		buf = append(buf, `\t`...)
+	case '\v':
+		buf = append(buf, `\v`...)
+	default:
+		switch {
+		case r < ' ' || r == 0x7f:
+			buf = append(buf, `\x`...)
+			buf = append(buf, lowerhex[byte(r)>>4])
+			buf = append(buf, lowerhex[byte(r)&0xF])
+		case !utf8.ValidRune(r):
+			r = 0xFFFD
+			fallthrough
+		case r < 0x10000:
+			buf = append(buf, `\u`...)
+			for s := 12; s >= 0; s -= 4 {
+				buf = append(buf, lowerhex[r>>uint(s)&0xF])
+			}
+		default:
+			buf = append(buf, `\U`...)
+			for s := 28; s >= 0; s -= 4 {
+				buf = append(buf, lowerhex[r>>uint(s)&0xF])
+			}
+		}
+	}
+	return buf
+}
+
+// IsPrint reports whether the rune is defined as printable by Go, with the
+// same definition as unicode.IsPrint: letters, marks, numbers, punctuation,
+// symbols, and the ASCII space character, from categories L, M, N, P, S and
+// the ASCII space character. Within Latin-1, that means 0x20 through 0x7E
+// and 0xA1 through 0xFF except the soft hyphen (0xAD); the control
+// characters and the non-breaking space (0xA0) are not printable.
+func IsPrint(r rune) bool {
+	// Fast check for Latin-1.
+	if r <= 0xFF {
+		if 0x20 <= r && r <= 0x7E {
+			// All the ASCII is printable from space through DEL-1.
+			return true
+		}
+		if 0xA1 <= r && r <= 0xFF {
+			// Similarly for Latin-1.
+			return r != 0xAD
+		}
+		return false
+	}
+
+	if 0 <= r && r < 1<<16 {
+		rr := uint16(r)
+		return is16(isPrint16, rr) && !isExcluded16(isNotPrint16, rr)
+	}
+
+	rr := uint32(r)
+	return is32(isPrint32, rr) && !isExcluded32(isNotPrint32, rr)
+}
+
+// IsGraphic reports whether the rune is defined as a Graphic character by
+// Unicode. Such characters include letters, marks, numbers, punctuation,
+// symbols, and spaces, from categories L, M, N, P, S, Zs.
+func IsGraphic(r rune) bool {
+	return IsPrint(r) || isInGraphicList(r)
+}
+
+// is16 reports whether r is in the sorted list of 16-bit (lo, hi) range
+// pairs, using binary search for larger tables and a linear scan otherwise.
+func is16(ranges []uint16, r uint16) bool {
+	if len(ranges) <= 18 {
+		for i := 0; i < len(ranges); i += 2 {
+			if r < ranges[i] {
+				return false
+			}
+			if r <= ranges[i+1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		mid := lo + (hi-lo)/2&^1 // ensure mid is even
+		if r >= ranges[mid] && r <= ranges[mid+1] {
+			return true
+		}
+		if r < ranges[mid] {
+			hi = mid
+		} else {
+			lo = mid + 2
+		}
+	}
+	return false
+}
+
+// isExcluded16 reports whether r is in the sorted list of individually
+// excluded 16-bit code points, via binary search.
+func isExcluded16(excluded []uint16, r uint16) bool {
+	lo, hi := 0, len(excluded)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case excluded[mid] == r:
+			return true
+		case excluded[mid] < r:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return false
+}
+
+// isExcluded32 is the 32-bit analog of isExcluded16.
+func isExcluded32(excluded []uint32, r uint32) bool {
+	lo, hi := 0, len(excluded)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case excluded[mid] == r:
+			return true
+		case excluded[mid] < r:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return false
+}
+
+// is32 is the 32-bit analog of is16.
+func is32(ranges []uint32, r uint32) bool {
+	if len(ranges) <= 18 {
+		for i := 0; i < len(ranges); i += 2 {
+			if r < ranges[i] {
+				return false
+			}
+			if r <= ranges[i+1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		mid := lo + (hi-lo)/2&^1 // ensure mid is even
+		if r >= ranges[mid] && r <= ranges[mid+1] {
+			return true
+		}
+		if r < ranges[mid] {
+			hi = mid
+		} else {
+			lo = mid + 2
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package compat
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var quoteTests = []struct {
+	in    string
+	out   string
+	ascii string
+}{
+	{"\a\b\f\r\n\t\v", `"\a\b\f\r\n\t\v"`, `"\a\b\f\r\n\t\v"`},
+	{"\\", `"\\"`, `"\\"`},
+	{"abc\xffdef", `"abc\xffdef"`, `"abc\xffdef"`},
+	{"☺", `"☺"`, `"\u263a"`},
+	{"\U0001D11E", `"𝄞"`, `"\U0001d11e"`},
+	{"", `""`, `""`},
+	{"Hello, world", `"Hello, world"`, `"Hello, world"`},
+}
+
+func TestQuote(t *testing.T) {
+	for _, tt := range quoteTests {
+		if got := Quote(tt.in); got != tt.out {
+			t.Errorf("Quote(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+		if got := string(AppendQuote([]byte("prefix:"), tt.in)); got != "prefix:"+tt.out {
+			t.Errorf("AppendQuote(%q) = %q, want %q", tt.in, got, "prefix:"+tt.out)
+		}
+	}
+}
+
+func TestQuoteToASCII(t *testing.T) {
+	for _, tt := range quoteTests {
+		if got := QuoteToASCII(tt.in); got != tt.ascii {
+			t.Errorf("QuoteToASCII(%q) = %q, want %q", tt.in, got, tt.ascii)
+		}
+	}
+}
+
+func TestQuoteRune(t *testing.T) {
+	tests := []struct {
+		in  rune
+		out string
+	}{
+		{'a', `'a'`},
+		{'\'', `'\''`},
+		{'"', `'"'`},
+		{'\\', `'\\'`},
+		{'\n', `'\n'`},
+		{0x263a, `'☺'`},
+		{utf8.RuneError, `'�'`},
+		{-1, `'�'`},
+	}
+	for _, tt := range tests {
+		if got := QuoteRune(tt.in); got != tt.out {
+			t.Errorf("QuoteRune(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestQuoteToGraphic(t *testing.T) {
+	if got := QuoteToGraphic(" "); got != "\" \"" {
+		t.Errorf("QuoteToGraphic(nbsp) = %q, want it to pass the non-breaking space through unescaped", got)
+	}
+	if got := Quote(" "); got == "\" \"" {
+		t.Errorf("Quote(nbsp) should escape the non-breaking space, got %q", got)
+	}
+}
+
+func TestIsPrint(t *testing.T) {
+	// Thai, Tamil, Ethiopic, Cherokee, and a supplementary-plane emoji: all
+	// printable per unicode.IsPrint/strconv.IsPrint, spanning scripts well
+	// outside the handful of blocks the table used to cover.
+	printable := []rune{'a', 'Z', '0', ' ', '~', 0x00A1, 0x0100, 0x4E2D, 0x1D11E,
+		0x0E01, 0x0B95, 0x1200, 0x13A0, 0x1F900}
+	for _, r := range printable {
+		if !IsPrint(r) {
+			t.Errorf("IsPrint(%U) = false, want true", r)
+		}
+	}
+	notPrintable := []rune{0, '\n', '\t', 0x7f, 0xAD, 0x00A0, 0x0378}
+	for _, r := range notPrintable {
+		if IsPrint(r) {
+			t.Errorf("IsPrint(%U) = true, want false", r)
+		}
+	}
+}
+
+func TestIsGraphic(t *testing.T) {
+	if !IsGraphic(0x00A0) {
+		t.Errorf("IsGraphic(nbsp) = false, want true")
+	}
+	if IsGraphic(0) {
+		t.Errorf("IsGraphic(NUL) = true, want false")
+	}
+	// A supplementary-plane rune must not alias onto the BMP isGraphic list
+	// by truncation, e.g. 0x100A0 & 0xFFFF == 0x00A0 (nbsp).
+	if isInGraphicList(0x100A0) {
+		t.Errorf("isInGraphicList(U+100A0) = true, want false")
+	}
+}
+
+// TestQuoteRoundTrip verifies that every literal produced by Quote is
+// accepted back by the existing unquote implementation and reproduces the
+// original string.
+func TestQuoteRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"plain",
+		"line1\nline2",
+		"tab\tquote\"back`slash\\",
+		"unicode: 世界 ☺ 𝄞",
+		"\x00\x01\x1f\x7f",
+		string([]byte{0xff, 0xfe}),
+	}
+	for _, in := range inputs {
+		q := Quote(in)
+		out, rem, err := unquote(q, true)
+		if err != nil {
+			t.Errorf("unquote(Quote(%q)) failed: %v", in, err)
+			continue
+		}
+		if rem != "" {
+			t.Errorf("unquote(Quote(%q)) left remainder %q", in, rem)
+		}
+		if out != in {
+			t.Errorf("round trip mismatch: got %q, want %q", out, in)
+		}
+	}
+}
+
+func TestQuoteToASCIINoMultibyte(t *testing.T) {
+	q := QuoteToASCII("世界")
+	if strings.ContainsAny(q[1:len(q)-1], "世界") {
+		t.Errorf("QuoteToASCII(%q) = %q, want only ASCII bytes between quotes", "世界", q)
+	}
+}
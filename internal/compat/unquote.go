@@ -0,0 +1,112 @@
+package compat
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Unquote interprets s as a single-quoted, double-quoted, or backquoted Go
+// string literal, returning the string value that s quotes. For single
+// quotes it returns the one-rune string; for double and back quotes it
+// returns the decoded value. Unquote requires that all of s, not just a
+// prefix, be a valid literal.
+func Unquote(s string) (string, error) {
+	out, rem, err := unquote(s, true)
+	if err == nil && len(rem) != 0 {
+		return "", ErrSyntax
+	}
+	return out, err
+}
+
+// UnquotePrefix is like Unquote, but only requires that s begin with a
+// valid quoted string rather than consist of one entirely. It returns the
+// decoded value together with rem, the remainder of s after the literal,
+// mirroring how QuotedPrefix reports the unconsumed tail of its verbatim
+// match.
+func UnquotePrefix(s string) (value, rem string, err error) {
+	return unquote(s, true)
+}
+
+// A ScanError reports a failure to scan a quoted token, together with the
+// byte offset within the Scanner's input at which the failure occurred.
+type ScanError struct {
+	Offset int
+	Err    error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("compat: invalid quoted token at offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// Scanner splits a sequence of quoted string literals, separated by any of
+// a caller-supplied set of delimiter bytes, out of an input and unescapes
+// each one in turn. It reuses the same single-, double-, and backtick-quote
+// grammar as Unquote, so values of any of the three quote styles may be
+// mixed freely within the scanned input.
+type Scanner struct {
+	buf    string
+	offset int
+}
+
+// NewScanner returns a Scanner that reads successive quoted tokens from s.
+func NewScanner(s string) *Scanner {
+	return &Scanner{buf: s}
+}
+
+// NewScannerFromReader drains r and returns a Scanner over its contents.
+func NewScannerFromReader(r io.RuneReader) (*Scanner, error) {
+	var b strings.Builder
+	for {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.WriteRune(c)
+	}
+	return NewScanner(b.String()), nil
+}
+
+// Done reports whether the Scanner has no more input left to scan.
+func (s *Scanner) Done() bool {
+	return s.offset >= len(s.buf)
+}
+
+// Offset returns the Scanner's current byte offset within its input, for
+// use in error reporting alongside or in place of ScanError.
+func (s *Scanner) Offset() int {
+	return s.offset
+}
+
+// Scan reads and unescapes the next quoted token, then consumes a single
+// trailing delimiter byte (one of delims) if one follows the token. It
+// returns io.EOF once the input is exhausted, and a *ScanError if the next
+// token is not a validly quoted literal or is not followed by either a
+// delimiter or the end of the input.
+func (s *Scanner) Scan(delims string) (string, error) {
+	if s.Done() {
+		return "", io.EOF
+	}
+	rest := s.buf[s.offset:]
+	value, rem, err := unquote(rest, true)
+	if err != nil {
+		return "", &ScanError{Offset: s.offset, Err: err}
+	}
+	s.offset += len(rest) - len(rem)
+	switch {
+	case len(rem) == 0:
+		// End of input; nothing left to consume.
+	case strings.IndexByte(delims, rem[0]) >= 0:
+		s.offset++
+	default:
+		return "", &ScanError{Offset: s.offset, Err: ErrSyntax}
+	}
+	return value, nil
+}
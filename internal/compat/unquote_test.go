@@ -0,0 +1,104 @@
+package compat
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{`"hello"`, "hello", false},
+		{`'a'`, "a", false},
+		{"`raw\r\nstring`", "raw\nstring", false},
+		{`"tab\tnewline\n"`, "tab\tnewline\n", false},
+		{`"unterminated`, "", true},
+		{`"ok" trailing garbage`, "", true},
+		{`not quoted at all`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := Unquote(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unquote(%q) = %q, nil; want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unquote(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unquote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUnquotePrefix(t *testing.T) {
+	value, rem, err := UnquotePrefix(`"hello" world`)
+	if err != nil {
+		t.Fatalf("UnquotePrefix returned error: %v", err)
+	}
+	if value != "hello" {
+		t.Errorf("value = %q, want %q", value, "hello")
+	}
+	if rem != " world" {
+		t.Errorf("rem = %q, want %q", rem, " world")
+	}
+}
+
+func TestScanner(t *testing.T) {
+	sc := NewScanner(`"key with spaces"="value with \"quotes\"",'x'=` + "`raw`")
+	var got []string
+	for !sc.Done() {
+		v, err := sc.Scan("=,")
+		if err != nil {
+			t.Fatalf("Scan() error: %v", err)
+		}
+		got = append(got, v)
+	}
+	want := []string{"key with spaces", `value with "quotes"`, "x", "raw"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d tokens %q, want %d tokens %q", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("token %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScannerEOF(t *testing.T) {
+	sc := NewScanner("")
+	if _, err := sc.Scan("="); !errors.Is(err, io.EOF) {
+		t.Errorf("Scan() on empty input = %v, want io.EOF", err)
+	}
+}
+
+func TestScannerBadToken(t *testing.T) {
+	sc := NewScanner(`"unterminated`)
+	_, err := sc.Scan("=")
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("Scan() error = %v, want *ScanError", err)
+	}
+}
+
+func TestNewScannerFromReader(t *testing.T) {
+	sc, err := NewScannerFromReader(strings.NewReader(`"a"="b"`))
+	if err != nil {
+		t.Fatalf("NewScannerFromReader returned error: %v", err)
+	}
+	v, err := sc.Scan("=")
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if v != "a" {
+		t.Errorf("Scan() = %q, want %q", v, "a")
+	}
+}